@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LeoTapok/khinsider-parser/internal/khinsider"
+)
+
+var (
+	getFormats      []string
+	getOut          string
+	getWorkers      int
+	getLyrics       bool
+	getLyricsArtist string
+	getCover        bool
+	getFromFile     string
+	getLayout       string
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get <album...>",
+	Short: "Download one or more albums",
+	Long: "Download one or more albums given as khinsider URLs or album IDs. " +
+		"Use --from-file to queue additional albums from a text file, one per line.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		albums := append([]string{}, args...)
+
+		if getFromFile != "" {
+			fromFile, err := readAlbumList(getFromFile)
+			if err != nil {
+				return fmt.Errorf("failed to read --from-file: %w", err)
+			}
+			albums = append(albums, fromFile...)
+		}
+
+		if len(albums) == 0 {
+			return fmt.Errorf("no albums specified: pass one or more album URLs/IDs, or --from-file")
+		}
+
+		for _, idOrURL := range albums {
+			if err := getOne(idOrURL); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", idOrURL, err)
+			}
+		}
+
+		return nil
+	},
+}
+
+// getOne скачивает один альбом: парсит его страницу, тянет треки и, если
+// запрошено, тексты песен.
+func getOne(idOrURL string) error {
+	album, err := khinsider.ParseAlbum(khinsider.ResolveAlbumURL(idOrURL))
+	if err != nil {
+		return err
+	}
+
+	opts := khinsider.DownloadOptions{
+		Formats:    getFormats,
+		NumWorkers: getWorkers,
+		Layout:     getLayout,
+		EmbedCover: getCover,
+	}
+	if err := album.Download(getOut, opts); err != nil {
+		return err
+	}
+
+	if getLyrics {
+		if err := album.DownloadLyrics(getOut, opts.Layout, getLyricsArtist); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readAlbumList читает список URL/ID альбомов из текстового файла, по
+// одному на строку; пустые строки и строки, начинающиеся с "#", пропускаются.
+func readAlbumList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var albums []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		albums = append(albums, line)
+	}
+
+	return albums, scanner.Err()
+}
+
+func init() {
+	getCmd.Flags().StringSliceVar(&getFormats, "format", nil, "preferred formats in order, e.g. flac,mp3")
+	getCmd.Flags().StringVar(&getOut, "out", "./music", "output directory, laid out as {artist}/{album}/{track}")
+	getCmd.Flags().IntVar(&getWorkers, "workers", 5, "number of concurrent downloads")
+	getCmd.Flags().StringVar(&getLayout, "layout", "", "path template relative to --out, with {artist}, {album}, {track} (default \"{artist}/{album}/{track}\")")
+	getCmd.Flags().BoolVar(&getLyrics, "lyrics", false, "also download synced lyrics as .lrc files")
+	getCmd.Flags().StringVar(&getLyricsArtist, "lyrics-artist", "", "artist/composer name to use for lyrics lookup (default: album developer, often wrong for game OSTs)")
+	getCmd.Flags().BoolVar(&getCover, "cover", true, "embed album cover art into downloaded tracks")
+	getCmd.Flags().StringVar(&getFromFile, "from-file", "", "read additional album URLs/IDs from a file, one per line")
+
+	rootCmd.AddCommand(getCmd)
+}
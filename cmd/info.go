@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LeoTapok/khinsider-parser/internal/khinsider"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info <album-url-or-id>",
+	Short: "Print parsed album metadata, track list and available formats without downloading",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		album, err := khinsider.ParseAlbum(khinsider.ResolveAlbumURL(args[0]))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Name:      %s\n", album.Name)
+		fmt.Printf("Year:      %s\n", album.Year)
+		fmt.Printf("Platforms: %s\n", strings.Join(album.Platforms, ", "))
+		fmt.Printf("Developer: %s\n", album.Developer)
+		fmt.Printf("Publisher: %s\n", album.Publisher)
+		fmt.Printf("Formats:   %s\n", strings.Join(album.Formats, ", "))
+		fmt.Printf("Tracks:    %d\n\n", len(album.Songs))
+
+		for i, song := range album.Songs {
+			fmt.Printf("%3d. %s\n", i+1, song.Name)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
@@ -0,0 +1,16 @@
+// Package cmd содержит подкоманды CLI khinsider-parser: search, info и get.
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "khinsider",
+	Short: "Search, inspect and download game soundtracks from downloads.khinsider.com",
+}
+
+// Execute запускает корневую команду CLI.
+func Execute() error {
+	return rootCmd.Execute()
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LeoTapok/khinsider-parser/internal/khinsider"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search for albums by name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, err := khinsider.Search(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(results) == 0 {
+			fmt.Println("No albums found.")
+			return nil
+		}
+
+		for _, r := range results {
+			fmt.Printf("%s\t%s\t%s\t%s\n", r.ID, r.Title, r.Platform, r.Year)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,63 @@
+// Package httpx содержит общую для пакетов khinsider и lyrics логику
+// вежливых HTTP-запросов: ограничение частоты и повтор с экспоненциальной
+// задержкой при 429/5xx и временных сетевых ошибках.
+package httpx
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RetryingClient — HTTP-клиент, который перед каждым запросом ждёт Limiter и
+// устанавливает UserAgent, а при 429/5xx или временной сетевой ошибке
+// повторяет запрос с экспоненциальной задержкой до MaxAttempts раз.
+type RetryingClient struct {
+	Client      *http.Client
+	Limiter     *rate.Limiter
+	UserAgent   string
+	MaxAttempts int
+}
+
+// Do выполняет req через c.Client, соблюдая лимит запросов и устанавливая
+// UserAgent. Запросы, завершившиеся с 429/5xx или временной сетевой
+// ошибкой, повторяются с экспоненциальной задержкой.
+func (c *RetryingClient) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	var lastErr error
+	for attempt := 0; attempt < c.MaxAttempts; attempt++ {
+		if err := c.Limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		res, err := c.Client.Do(req)
+		switch {
+		case err != nil:
+			lastErr = err
+		case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500:
+			lastErr = fmt.Errorf("request to %s failed with status %d", req.URL, res.StatusCode)
+			res.Body.Close()
+		default:
+			return res, nil
+		}
+
+		if attempt < c.MaxAttempts-1 {
+			time.Sleep(Backoff(attempt))
+		}
+	}
+
+	return nil, fmt.Errorf("giving up on %s after %d attempts: %w", req.URL, c.MaxAttempts, lastErr)
+}
+
+// Backoff возвращает задержку перед попыткой attempt+1: экспоненциально
+// растущая база плюс случайный джиттер, чтобы параллельные воркеры не
+// повторяли запросы синхронно.
+func Backoff(attempt int) time.Duration {
+	base := time.Duration(math.Pow(2, float64(attempt))) * 200 * time.Millisecond
+	return base + time.Duration(rand.Int63n(int64(base/2+1)))
+}
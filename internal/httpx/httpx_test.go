@@ -0,0 +1,18 @@
+package httpx
+
+import "testing"
+
+func TestBackoffGrowsWithJitterBound(t *testing.T) {
+	for attempt := 0; attempt < 5; attempt++ {
+		base := (1 << attempt) * 200 // milliseconds, mirrors 2^attempt * 200ms
+		min := base
+		max := base + base/2
+
+		for i := 0; i < 20; i++ {
+			got := Backoff(attempt).Milliseconds()
+			if got < int64(min) || got > int64(max) {
+				t.Fatalf("Backoff(%d) = %dms, want in [%d, %d]ms", attempt, got, min, max)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package khinsider
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsVerifiedSizeMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.mp3")
+
+	if isVerified(path) {
+		t.Fatal("isVerified() = true before the file even exists")
+	}
+
+	if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if isVerified(path) {
+		t.Fatal("isVerified() = true with no size manifest recorded")
+	}
+
+	if err := writeSizeManifest(path, 10); err != nil {
+		t.Fatalf("writeSizeManifest() error = %v", err)
+	}
+	if !isVerified(path) {
+		t.Fatal("isVerified() = false for a file matching its recorded size")
+	}
+
+	// Усечённый/повреждённый файл: то, что лежит на диске, больше не
+	// совпадает по размеру с независимо известным манифестом.
+	if err := os.WriteFile(path, []byte("012"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if isVerified(path) {
+		t.Fatal("isVerified() = true for a file shorter than its recorded size")
+	}
+}
+
+// TestDownloadFileResumesAfterTruncation имитирует обрыв соединения
+// посередине закачки: первая попытка получает укороченный ответ и
+// завершается ошибкой, вторая докачивает остаток через Range-запрос. Итоговый
+// файл должен совпадать с содержимым на сервере и проходить isVerified по
+// размеру, независимо записанному в манифест ещё до начала закачки.
+func TestDownloadFileResumesAfterTruncation(t *testing.T) {
+	full := bytes.Repeat([]byte("khinsider-test-data-"), 100)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", fmt.Sprint(len(full)))
+			return
+		}
+
+		if rng := r.Header.Get("Range"); rng != "" {
+			var start int
+			fmt.Sscanf(rng, "bytes=%d-", &start)
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(full)-1, len(full)))
+			w.Header().Set("Content-Length", fmt.Sprint(len(full)-start))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(full[start:])
+			return
+		}
+
+		// Первая (не-Range) попытка: отдаём только половину заявленного
+		// Content-Length, имитируя обрыв соединения на флаки-сети.
+		w.Header().Set("Content-Length", fmt.Sprint(len(full)))
+		w.Write(full[:len(full)/2])
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "track.mp3")
+
+	if err := downloadFile(srv.URL, path); err == nil {
+		t.Fatal("downloadFile() on truncated response, want error, got nil")
+	}
+	if isVerified(path) {
+		t.Fatal("isVerified() = true for a download that never completed")
+	}
+
+	if err := downloadFile(srv.URL, path); err != nil {
+		t.Fatalf("downloadFile() resume error = %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !bytes.Equal(got, full) {
+		t.Fatalf("downloaded file content mismatch: got %d bytes, want %d bytes", len(got), len(full))
+	}
+	if !isVerified(path) {
+		t.Fatal("isVerified() = false for a fully resumed, correctly sized download")
+	}
+}
+
+// TestDownloadFileRedownloadsOversizedPart проверяет, что ".part" крупнее,
+// чем текущий Content-Length на сервере (битая прошлая закачка), не
+// докачивается Range-запросом (сервер ответил бы 416), а перекачивается
+// заново.
+func TestDownloadFileRedownloadsOversizedPart(t *testing.T) {
+	full := []byte("short and final content")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", fmt.Sprint(len(full)))
+			return
+		}
+		if r.Header.Get("Range") != "" {
+			t.Fatalf("unexpected Range request for an oversized .part: %s", r.Header.Get("Range"))
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(full)))
+		w.Write(full)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "track.mp3")
+	if err := os.WriteFile(path+".part", bytes.Repeat([]byte("x"), len(full)*2), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := downloadFile(srv.URL, path); err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !bytes.Equal(got, full) {
+		t.Fatalf("downloaded file content = %q, want %q", got, full)
+	}
+}
@@ -0,0 +1,67 @@
+package khinsider
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/LeoTapok/khinsider-parser/internal/httpx"
+)
+
+const userAgent = "khinsider-parser/1.0 (+https://github.com/LeoTapok/khinsider-parser)"
+
+const maxAttempts = 5
+
+// retryClient — общий клиент для всех запросов к downloads.khinsider.com: с
+// cookie jar, таймаутом, ограничением частоты (по умолчанию 2 запроса в
+// секунду) и повтором с экспоненциальной задержкой при 429/5xx.
+var retryClient = &httpx.RetryingClient{
+	Client:      newHTTPClient(),
+	Limiter:     rate.NewLimiter(2, 1),
+	UserAgent:   userAgent,
+	MaxAttempts: maxAttempts,
+}
+
+// lyricsClient — отдельный вежливый клиент для запросов к lrclib.net: своё
+// ограничение частоты (1 запрос в секунду — lrclib бесплатный сторонний
+// сервис, не сайт, который мы в остальном скрапим) и тот же повтор с
+// экспоненциальной задержкой при 429/5xx.
+var lyricsClient = &httpx.RetryingClient{
+	Client:      &http.Client{Timeout: 30 * time.Second},
+	Limiter:     rate.NewLimiter(1, 1),
+	UserAgent:   userAgent,
+	MaxAttempts: maxAttempts,
+}
+
+func newHTTPClient() *http.Client {
+	jar, _ := cookiejar.New(nil)
+	return &http.Client{Jar: jar, Timeout: 30 * time.Second}
+}
+
+// get выполняет GET-запрос через общий клиент.
+func get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return retryClient.Do(req)
+}
+
+// head выполняет HEAD-запрос через общий клиент.
+func head(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return retryClient.Do(req)
+}
+
+// doRequest выполняет произвольный запрос через общий клиент, соблюдая
+// лимит запросов и устанавливая собственный User-Agent. Запросы,
+// завершившиеся с 429/5xx или временной сетевой ошибкой, повторяются с
+// экспоненциальной задержкой.
+func doRequest(req *http.Request) (*http.Response, error) {
+	return retryClient.Do(req)
+}
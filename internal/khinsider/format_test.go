@@ -0,0 +1,61 @@
+package khinsider
+
+import "testing"
+
+func TestFormatFromHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{"MP3", "MP3", true},
+		{" flac ", "FLAC", true},
+		{"ogg", "OGG", true},
+		{"M4A", "M4A", true},
+		{"Bitrate", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := formatFromHeader(tt.header)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("formatFromHeader(%q) = (%q, %v), want (%q, %v)", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPickFile(t *testing.T) {
+	song := Song{
+		Files: []File{
+			{URL: "a.mp3", Format: "MP3"},
+			{URL: "a.flac", Format: "FLAC"},
+		},
+	}
+
+	t.Run("first matching preference wins", func(t *testing.T) {
+		file, ok := pickFile(song, []string{"flac", "mp3"})
+		if !ok || file.Format != "FLAC" {
+			t.Fatalf("pickFile() = (%+v, %v), want FLAC", file, ok)
+		}
+	})
+
+	t.Run("empty preference falls back to preferredFormats", func(t *testing.T) {
+		file, ok := pickFile(song, nil)
+		if !ok || file.Format != "FLAC" {
+			t.Fatalf("pickFile() = (%+v, %v), want FLAC", file, ok)
+		}
+	})
+
+	t.Run("no requested format matches falls back to first file", func(t *testing.T) {
+		file, ok := pickFile(song, []string{"OGG"})
+		if !ok || file.Format != "MP3" {
+			t.Fatalf("pickFile() = (%+v, %v), want MP3", file, ok)
+		}
+	})
+
+	t.Run("no files available", func(t *testing.T) {
+		if _, ok := pickFile(Song{}, []string{"MP3"}); ok {
+			t.Fatalf("pickFile() on song with no files should return ok=false")
+		}
+	})
+}
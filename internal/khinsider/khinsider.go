@@ -0,0 +1,659 @@
+// Package khinsider реализует скрапинг downloads.khinsider.com: поиск
+// альбомов, разбор страницы альбома и параллельную загрузку треков с
+// тегированием и текстами песен.
+package khinsider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/LeoTapok/khinsider-parser/internal/lyrics"
+	"github.com/LeoTapok/khinsider-parser/internal/tag"
+)
+
+// maxConcurrentSongFetches ограничивает число одновременных запросов
+// страниц отдельных песен при разборе альбома.
+const maxConcurrentSongFetches = 10
+
+const baseURL = "https://downloads.khinsider.com"
+const albumBaseURL = baseURL + "/game-soundtracks/album/"
+
+// preferredFormats задаёт порядок предпочтения форматов, если пользователь
+// явно не указал свой список. OGG сюда намеренно не входит: tag.Apply пока
+// не умеет тегировать этот формат, и трек в нём скачался бы без тегов и
+// обложки без какой-либо явной причины для пользователя.
+var preferredFormats = []string{"FLAC", "M4A", "MP3"}
+
+// Album описывает альбом с KHInsider.
+type Album struct {
+	ID        string
+	URL       string
+	Name      string
+	Songs     []Song
+	Formats   []string
+	CoverURL  string
+	Year      string
+	Platforms []string
+	Developer string
+	Publisher string
+}
+
+// Song описывает песню, принадлежащую саундтреку.
+type Song struct {
+	URL   string
+	Name  string
+	Files []File
+}
+
+// File описывает файл песни в конкретном формате, который можно скачать.
+type File struct {
+	URL    string
+	Format string // например "MP3", "FLAC", "OGG", "M4A"
+}
+
+// SearchResult — одна строка результатов поиска по альбомам.
+type SearchResult struct {
+	ID       string
+	Title    string
+	URL      string
+	Platform string
+	Year     string
+}
+
+// metadataLineRe разбирает блок метаданных альбома вида "Label: Value",
+// по одной паре на строку.
+var metadataLineRe = regexp.MustCompile(`(?m)^\s*(Platforms|Year|Developed by|Published by)\s*:\s*(.+?)\s*$`)
+
+// ResolveAlbumURL приводит идентификатор или URL альбома к полному URL
+// страницы альбома на downloads.khinsider.com.
+func ResolveAlbumURL(idOrURL string) string {
+	if strings.HasPrefix(idOrURL, "http://") || strings.HasPrefix(idOrURL, "https://") {
+		return idOrURL
+	}
+	return albumBaseURL + idOrURL
+}
+
+// fetchPage загружает HTML-страницу и возвращает документ goquery.
+func fetchPage(pageURL string) (*goquery.Document, error) {
+	res, err := get(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to fetch page: status code %d", res.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// Search ищет альбомы по запросу через
+// https://downloads.khinsider.com/search?search=....
+func Search(query string) ([]SearchResult, error) {
+	doc, err := fetchPage(baseURL + "/search?search=" + url.QueryEscape(query))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	doc.Find("table.albumList tr").Each(func(i int, row *goquery.Selection) {
+		link := row.Find("a[href*='/game-soundtracks/album/']").First()
+		href, exists := link.Attr("href")
+		if !exists {
+			return
+		}
+
+		result := SearchResult{
+			Title: strings.TrimSpace(link.Text()),
+			URL:   baseURL + href,
+			ID:    strings.TrimPrefix(href, "/game-soundtracks/album/"),
+		}
+
+		cells := row.Find("td")
+		if cells.Length() >= 3 {
+			result.Platform = strings.TrimSpace(cells.Eq(1).Text())
+			result.Year = strings.TrimSpace(cells.Eq(2).Text())
+		}
+
+		results = append(results, result)
+	})
+
+	return results, nil
+}
+
+// ParseAlbum загружает и разбирает страницу альбома: список треков с их
+// форматами, обложку и метаданные (год, платформы, разработчик, издатель).
+func ParseAlbum(albumURL string) (*Album, error) {
+	docAlb, err := fetchPage(albumURL)
+	if err != nil {
+		return nil, err
+	}
+
+	album := &Album{
+		ID:    strings.TrimSuffix(strings.TrimPrefix(albumURL, albumBaseURL), "/"),
+		URL:   albumURL,
+		Name:  strings.TrimSpace(docAlb.Find("h2").First().Text()),
+		Songs: []Song{},
+	}
+
+	// Собираем список форматов, перечисленных в шапке таблицы треков
+	// (колонки вида "MP3", "FLAC", "OGG", "M4A").
+	docAlb.Find("table#songlist th").Each(func(i int, th *goquery.Selection) {
+		if format, ok := formatFromHeader(th.Text()); ok {
+			album.Formats = append(album.Formats, format)
+		}
+	})
+
+	parseAlbumMetadata(docAlb, album)
+	album.CoverURL, _ = docAlb.Find("#pageContent img").First().Attr("src")
+
+	// Сначала собираем ссылки и названия треков в исходном порядке таблицы,
+	// а уже их страницы тянем параллельно.
+	var entries []struct{ url, name string }
+	docAlb.Find("table#songlist tr").Each(func(i int, s *goquery.Selection) {
+		link := s.Find("a")
+		if songURL, exists := link.Attr("href"); exists {
+			entries = append(entries, struct{ url, name string }{baseURL + songURL, link.Text()})
+		}
+	})
+
+	songs := make([]Song, len(entries))
+
+	g, ctx := errgroup.WithContext(context.Background())
+	sem := make(chan struct{}, maxConcurrentSongFetches)
+
+	for i, entry := range entries {
+		i, entry := i, entry
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+
+			files, err := getDownloadLink(entry.url)
+			if err != nil {
+				return fmt.Errorf("failed to get download link for song %s: %w", entry.name, err)
+			}
+
+			songs[i] = Song{URL: entry.url, Name: entry.name, Files: files}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	album.Songs = songs
+	return album, nil
+}
+
+// parseAlbumMetadata разбирает блок "Platforms / Year / Developed by /
+// Published by" на странице альбома и заполняет соответствующие поля album.
+func parseAlbumMetadata(doc *goquery.Document, album *Album) {
+	doc.Find("#pageContent p").Each(func(i int, p *goquery.Selection) {
+		for _, match := range metadataLineRe.FindAllStringSubmatch(p.Text(), -1) {
+			label, value := match[1], match[2]
+			switch label {
+			case "Platforms":
+				parts := strings.Split(value, ",")
+				for _, part := range parts {
+					if part = strings.TrimSpace(part); part != "" {
+						album.Platforms = append(album.Platforms, part)
+					}
+				}
+			case "Year":
+				album.Year = value
+			case "Developed by":
+				album.Developer = value
+			case "Published by":
+				album.Publisher = value
+			}
+		}
+	})
+}
+
+// formatFromHeader определяет, является ли заголовок колонки таблицы треков
+// названием формата закачки, и возвращает его в нормализованном виде.
+func formatFromHeader(header string) (string, bool) {
+	switch strings.ToUpper(strings.TrimSpace(header)) {
+	case "MP3", "FLAC", "OGG", "M4A":
+		return strings.ToUpper(strings.TrimSpace(header)), true
+	default:
+		return "", false
+	}
+}
+
+// getDownloadLink извлекает со страницы песни ссылки на файл во всех
+// доступных форматах (MP3, FLAC, OGG, M4A и т.д.), по одной на каждый
+// обнаруженный тег <a href="/soundtracks/...">.
+func getDownloadLink(songPageURL string) ([]File, error) {
+	res, err := get(songPageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to load song page: %d", res.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	doc.Find("a[href*='/soundtracks/']").Each(func(i int, a *goquery.Selection) {
+		href, exists := a.Attr("href")
+		if !exists {
+			return
+		}
+
+		ext := strings.ToUpper(strings.TrimPrefix(filepath.Ext(href), "."))
+		if ext == "" {
+			return
+		}
+
+		files = append(files, File{URL: href, Format: ext})
+	})
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("download link not found on song page")
+	}
+
+	return files, nil
+}
+
+// pickFile выбирает файл песни в соответствии со списком предпочитаемых
+// форматов, возвращая первое совпадение. Если ни один из запрошенных
+// форматов недоступен, возвращается первый попавшийся файл песни.
+func pickFile(song Song, formats []string) (File, bool) {
+	if len(formats) == 0 {
+		formats = preferredFormats
+	}
+
+	for _, format := range formats {
+		format = strings.ToUpper(format)
+		for _, file := range song.Files {
+			if file.Format == format {
+				return file, true
+			}
+		}
+	}
+
+	if len(song.Files) > 0 {
+		return song.Files[0], true
+	}
+
+	return File{}, false
+}
+
+// downloadFile загружает файл по URL и сохраняет его на диск. Загрузка
+// ведётся во временный "<path>.part" и при необходимости возобновляется с
+// места обрыва через Range-запросы; по завершении файл переименовывается в
+// path. Content-Length, сообщённый HEAD-запросом до начала закачки, пишется
+// в манифест "<path>.size" — в отличие от хеша, посчитанного по самим
+// скачанным байтам, это независимое от результата закачки значение, и оно
+// способно отличить усечённую или повреждённую закачку от настоящей.
+func downloadFile(fileURL string, path string) error {
+	partPath := path + ".part"
+
+	var existing int64
+	if info, err := os.Stat(partPath); err == nil {
+		existing = info.Size()
+	}
+
+	total := int64(-1)
+	acceptsRanges := false
+	if res, err := head(fileURL); err == nil {
+		res.Body.Close()
+		total = res.ContentLength
+		acceptsRanges = res.Header.Get("Accept-Ranges") == "bytes"
+	}
+
+	if total > 0 {
+		if err := writeSizeManifest(path, total); err != nil {
+			return err
+		}
+	}
+
+	// ".part" крупнее, чем сейчас сообщает сервер (битая прошлая закачка
+	// или файл на сервере изменился) — Range-запрос с таким смещением
+	// сервер отклонит 416-м, так что докачать его нельзя, качаем заново.
+	if total > 0 && existing > total {
+		existing = 0
+	}
+
+	// Файл уже полностью докачан в предыдущем запуске, осталось только
+	// переименовать его.
+	if existing > 0 && total > 0 && existing == total {
+		return finalizeDownload(partPath, path)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fileURL, nil)
+	if err != nil {
+		return err
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if existing > 0 && acceptsRanges {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", existing))
+		flags |= os.O_APPEND
+	} else {
+		existing = 0
+		flags |= os.O_TRUNC
+	}
+
+	res, err := doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("failed to download file: status code %d", res.StatusCode)
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, res.Body); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return finalizeDownload(partPath, path)
+}
+
+// finalizeDownload переименовывает завершённую закачку из partPath в path.
+func finalizeDownload(partPath, path string) error {
+	return os.Rename(partPath, path)
+}
+
+// isVerified сообщает, скачан ли файл по path целиком, сверяя его размер с
+// манифестом "<path>.size", записанным до начала закачки. Отсутствие файла
+// или манифеста считается неверифицированным состоянием — файл будет
+// перекачан.
+func isVerified(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	want, ok := readSizeManifest(path)
+	if !ok {
+		return false
+	}
+
+	return info.Size() == want
+}
+
+// writeSizeManifest сохраняет независимо известный размер файла (обычно —
+// Content-Length с HEAD-запроса) в "<path>.size", чтобы последующие запуски
+// могли отличить полностью скачанный файл от усечённого или повреждённого.
+func writeSizeManifest(path string, size int64) error {
+	return os.WriteFile(path+".size", []byte(strconv.FormatInt(size, 10)), 0644)
+}
+
+// readSizeManifest читает размер, записанный writeSizeManifest.
+func readSizeManifest(path string) (int64, bool) {
+	data, err := os.ReadFile(path + ".size")
+	if err != nil {
+		return 0, false
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return size, true
+}
+
+// downloadJob — задание воркера: песня и её порядковый номер в альбоме
+// (для тега трека).
+type downloadJob struct {
+	song  Song
+	track int
+}
+
+// fetchCover скачивает обложку альбома по a.CoverURL. Ошибки не фатальны:
+// отсутствие обложки не должно останавливать загрузку треков.
+func (a *Album) fetchCover() []byte {
+	if a.CoverURL == "" {
+		return nil
+	}
+
+	res, err := get(a.CoverURL)
+	if err != nil {
+		log.Printf("Failed to fetch cover: %v\n", err)
+		return nil
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		log.Printf("Failed to fetch cover: status code %d\n", res.StatusCode)
+		return nil
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		log.Printf("Failed to read cover: %v\n", err)
+		return nil
+	}
+
+	return data
+}
+
+// DownloadOptions настраивает поведение Album.Download.
+type DownloadOptions struct {
+	// Formats — форматы в порядке предпочтения; пусто значит
+	// preferredFormats.
+	Formats []string
+	// NumWorkers — количество одновременных закачек.
+	NumWorkers int
+	// Layout — шаблон пути трека относительно базовой директории, с
+	// подстановками {artist}, {album} и {track}. Пусто значит
+	// "{artist}/{album}/{track}".
+	Layout string
+	// EmbedCover отключает скачивание и встраивание обложки, если false.
+	EmbedCover bool
+}
+
+const defaultLayout = "{artist}/{album}/{track}"
+
+// trackPath строит путь файла трека (без расширения) относительно baseDir
+// по шаблону DownloadOptions.Layout.
+func (a *Album) trackPath(baseDir, layout, songName string) string {
+	if layout == "" {
+		layout = defaultLayout
+	}
+
+	artist := a.Developer
+	if artist == "" {
+		artist = "Unknown Artist"
+	}
+	album := a.Name
+	if album == "" {
+		album = a.ID
+	}
+
+	replacer := strings.NewReplacer(
+		"{artist}", sanitizeFileName(artist),
+		"{album}", sanitizeFileName(album),
+		"{track}", sanitizeFileName(songName),
+	)
+
+	return filepath.Join(baseDir, filepath.FromSlash(replacer.Replace(layout)))
+}
+
+// Download загружает все файлы песен альбома параллельно в соответствии с
+// opts, выбирая для каждой песни формат из opts.Formats (в порядке
+// предпочтения) и раскладывая файлы по opts.Layout.
+func (a *Album) Download(baseDir string, opts DownloadOptions) error {
+	if opts.NumWorkers <= 0 {
+		opts.NumWorkers = 1
+	}
+
+	var cover []byte
+	if opts.EmbedCover {
+		// Один раз скачиваем обложку альбома, чтобы не тянуть её на
+		// каждый трек.
+		cover = a.fetchCover()
+	}
+
+	// Создаём канал для задач загрузки
+	downloadJobs := make(chan downloadJob)
+	var wg sync.WaitGroup
+
+	// Запуск воркеров для выполнения загрузки
+	for i := 0; i < opts.NumWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range downloadJobs {
+				file, ok := pickFile(job.song, opts.Formats)
+				if !ok {
+					log.Printf("No downloadable files for %s\n", job.song.Name)
+					continue
+				}
+
+				// Берём расширение из самой ссылки, а не предполагаем
+				// конкретный формат.
+				ext := strings.ToLower(filepath.Ext(file.URL))
+				filePath := a.trackPath(baseDir, opts.Layout, job.song.Name) + ext
+
+				if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+					log.Printf("Failed to create directory for %s: %v\n", job.song.Name, err)
+					continue
+				}
+
+				if isVerified(filePath) {
+					fmt.Printf("Already verified, skipping: %s\n", job.song.Name)
+					continue
+				}
+
+				fmt.Printf("Downloading: %s\n", file.URL)
+
+				// Пытаемся скачать файл по прямой ссылке
+				err := downloadFile(file.URL, filePath)
+				if err != nil {
+					log.Printf("Failed to download %s: %v\n", job.song.Name, err)
+				} else {
+					fmt.Printf("Downloaded: %s\n", job.song.Name)
+
+					if err := tag.Apply(filePath, tag.Metadata{
+						Title:   job.song.Name,
+						Album:   a.Name,
+						Artist:  a.Developer,
+						Year:    a.Year,
+						Track:   job.track,
+						TrackOf: len(a.Songs),
+					}, cover); err != nil {
+						log.Printf("Failed to tag %s: %v\n", job.song.Name, err)
+					}
+				}
+
+				// Задержка между загрузками (необязательно)
+				time.Sleep(100 * time.Millisecond)
+			}
+		}()
+	}
+
+	// Отправляем задания на загрузку в канал
+	for i, song := range a.Songs {
+		downloadJobs <- downloadJob{song: song, track: i + 1}
+	}
+	close(downloadJobs) // Закрываем канал, чтобы сигнализировать воркерам об окончании заданий
+
+	// Ждём завершения всех воркеров
+	wg.Wait()
+	return nil
+}
+
+// DownloadLyrics запрашивает у lrclib.net текст для каждой песни альбома и,
+// если он найден, сохраняет его рядом с треком как "<название>.lrc".
+// Отсутствие текста для отдельной песни не прерывает загрузку остальных.
+//
+// lrclib индексирует саундтреки по имени композитора, а не студии-
+// разработчика или издателя, которых для альбома знает khinsider. Если
+// artist пуст, используется a.Developer — для многих игровых альбомов он
+// не совпадает с композитором, и поиск у lrclib просто ничего не найдёт;
+// вызывающий код должен передавать artist явно, когда знает настоящего
+// композитора.
+func (a *Album) DownloadLyrics(baseDir, layout, artist string) error {
+	if artist == "" {
+		artist = a.Developer
+	}
+
+	for _, song := range a.Songs {
+		result, err := lyrics.Fetch(lyricsClient, artist, song.Name, a.Name, 0)
+		if errors.Is(err, lyrics.ErrNotFound) {
+			log.Printf("No lyrics found for %s\n", song.Name)
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to fetch lyrics for %s: %v\n", song.Name, err)
+			continue
+		}
+
+		lrcPath := a.trackPath(baseDir, layout, song.Name) + ".lrc"
+		if err := os.MkdirAll(filepath.Dir(lrcPath), os.ModePerm); err != nil {
+			log.Printf("Failed to create directory for %s: %v\n", song.Name, err)
+			continue
+		}
+		if err := os.WriteFile(lrcPath, []byte(result.Text), 0644); err != nil {
+			log.Printf("Failed to save lyrics for %s: %v\n", song.Name, err)
+			continue
+		}
+
+		fmt.Printf("Saved lyrics: %s\n", song.Name)
+	}
+
+	return nil
+}
+
+// sanitizeFileName удаляет или заменяет недопустимые символы в имени файла
+func sanitizeFileName(name string) string {
+	// Заменяем двоеточие на дефис
+	name = strings.ReplaceAll(name, ":", "-")
+
+	// Удаляем все недопустимые символы, такие как специальные символы
+	re := regexp.MustCompile(`[<>:"/\\|?*]`)
+	name = re.ReplaceAllString(name, "")
+
+	// Убираем пробелы в начале и конце и заменяем их на подчеркивания
+	name = strings.TrimSpace(name)
+	name = strings.ReplaceAll(name, " ", "_")
+
+	return name
+}
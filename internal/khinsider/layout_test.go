@@ -0,0 +1,35 @@
+package khinsider
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAlbumTrackPath(t *testing.T) {
+	album := &Album{Name: "Best Album", Developer: "Cool Studio"}
+
+	t.Run("default layout", func(t *testing.T) {
+		got := album.trackPath("music", "", "01 Intro")
+		want := filepath.Join("music", "Cool_Studio", "Best_Album", "01_Intro")
+		if got != want {
+			t.Errorf("trackPath() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("custom layout", func(t *testing.T) {
+		got := album.trackPath("music", "{album}/{track}", "01 Intro")
+		want := filepath.Join("music", "Best_Album", "01_Intro")
+		if got != want {
+			t.Errorf("trackPath() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("missing developer and name fall back", func(t *testing.T) {
+		bare := &Album{ID: "some-id"}
+		got := bare.trackPath("music", "", "Song")
+		want := filepath.Join("music", "Unknown_Artist", "some-id", "Song")
+		if got != want {
+			t.Errorf("trackPath() = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package khinsider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseAlbumMetadata(t *testing.T) {
+	html := `<html><body><div id="pageContent">
+		<p>
+			Platforms: Nintendo Switch, PC
+			Year: 2023
+			Developed by: Some Studio
+			Published by: Some Publisher
+		</p>
+	</div></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("goquery.NewDocumentFromReader() error = %v", err)
+	}
+
+	album := &Album{}
+	parseAlbumMetadata(doc, album)
+
+	if album.Year != "2023" {
+		t.Errorf("Year = %q, want %q", album.Year, "2023")
+	}
+	if album.Developer != "Some Studio" {
+		t.Errorf("Developer = %q, want %q", album.Developer, "Some Studio")
+	}
+	if album.Publisher != "Some Publisher" {
+		t.Errorf("Publisher = %q, want %q", album.Publisher, "Some Publisher")
+	}
+	wantPlatforms := []string{"Nintendo Switch", "PC"}
+	if len(album.Platforms) != len(wantPlatforms) {
+		t.Fatalf("Platforms = %v, want %v", album.Platforms, wantPlatforms)
+	}
+	for i, p := range wantPlatforms {
+		if album.Platforms[i] != p {
+			t.Errorf("Platforms[%d] = %q, want %q", i, album.Platforms[i], p)
+		}
+	}
+}
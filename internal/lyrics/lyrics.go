@@ -0,0 +1,87 @@
+// Package lyrics запрашивает текст песни у lrclib.net (синхронизированный,
+// а если его нет — обычный) и возвращает его без изменений.
+package lyrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrNotFound возвращается, когда lrclib не нашёл ни синхронизированного,
+// ни обычного текста песни.
+var ErrNotFound = errors.New("lyrics: not found")
+
+// Result — текст песни, полученный от lrclib, и признак того, что он
+// синхронизирован по времени (LRC) или обычный (plain).
+type Result struct {
+	Text   string
+	Synced bool
+}
+
+type lrclibResponse struct {
+	SyncedLyrics string `json:"syncedLyrics"`
+	PlainLyrics  string `json:"plainLyrics"`
+}
+
+// Doer выполняет HTTP-запрос; этому интерфейсу удовлетворяет *http.Client,
+// а также любой клиент с собственным ограничением частоты и повтором
+// запросов.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Fetch запрашивает текст песни у lrclib.net по названию трека, альбома,
+// исполнителя и длительности (в секундах; 0, если неизвестна).
+// Если синхронизированный текст недоступен, возвращается обычный текст.
+// ErrNotFound означает, что lrclib не нашёл песню (404) или вернул её без
+// какого-либо текста.
+func Fetch(client Doer, artistName, trackName, albumName string, durationSeconds int) (*Result, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	query := url.Values{}
+	query.Set("artist_name", artistName)
+	query.Set("track_name", trackName)
+	if albumName != "" {
+		query.Set("album_name", albumName)
+	}
+	if durationSeconds > 0 {
+		query.Set("duration", fmt.Sprintf("%d", durationSeconds))
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://lrclib.net/api/get?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("lyrics: lrclib returned status %d", res.StatusCode)
+	}
+
+	var body lrclibResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("lyrics: decode response: %w", err)
+	}
+
+	if body.SyncedLyrics != "" {
+		return &Result{Text: body.SyncedLyrics, Synced: true}, nil
+	}
+	if body.PlainLyrics != "" {
+		return &Result{Text: body.PlainLyrics, Synced: false}, nil
+	}
+
+	return nil, ErrNotFound
+}
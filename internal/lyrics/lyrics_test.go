@@ -0,0 +1,91 @@
+package lyrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	t.Run("prefers synced lyrics", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"syncedLyrics":"[00:01.00]line one","plainLyrics":"line one"}`)
+		}))
+		defer srv.Close()
+
+		result, err := fetchFromServer(t, srv, "Composer", "Track", "Album", 0)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if !result.Synced || result.Text != "[00:01.00]line one" {
+			t.Errorf("Fetch() = %+v, want synced lyrics", result)
+		}
+	})
+
+	t.Run("falls back to plain lyrics", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"plainLyrics":"line one"}`)
+		}))
+		defer srv.Close()
+
+		result, err := fetchFromServer(t, srv, "Composer", "Track", "Album", 0)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if result.Synced || result.Text != "line one" {
+			t.Errorf("Fetch() = %+v, want plain lyrics", result)
+		}
+	})
+
+	t.Run("404 maps to ErrNotFound", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		_, err := fetchFromServer(t, srv, "Composer", "Track", "Album", 0)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Fetch() error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("empty body maps to ErrNotFound", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{}`)
+		}))
+		defer srv.Close()
+
+		_, err := fetchFromServer(t, srv, "Composer", "Track", "Album", 0)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Fetch() error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+// fetchFromServer вызывает Fetch, подменяя lrclib.net локальным тестовым
+// сервером srv через кастомный http.Client.Transport.
+func fetchFromServer(t *testing.T, srv *httptest.Server, artist, track, album string, duration int) (*Result, error) {
+	t.Helper()
+
+	client := &http.Client{
+		Transport: redirectTransport{target: srv.URL},
+	}
+	return Fetch(client, artist, track, album, duration)
+}
+
+// redirectTransport перенаправляет все запросы на target, сохраняя путь и
+// query исходного запроса к lrclib.net.
+type redirectTransport struct {
+	target string
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	targetURL := rt.target + req.URL.Path + "?" + req.URL.RawQuery
+	redirected, err := http.NewRequestWithContext(req.Context(), req.Method, targetURL, req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultTransport.RoundTrip(redirected)
+}
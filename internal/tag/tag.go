@@ -0,0 +1,148 @@
+// Package tag записывает метаданные (название, альбом, исполнитель, год,
+// номер трека, обложка) в уже скачанные аудиофайлы. Формат определяется по
+// расширению файла.
+package tag
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bogem/id3v2"
+	"github.com/go-flac/flacpicture"
+	"github.com/go-flac/flacvorbis"
+	"github.com/go-flac/go-flac"
+	"github.com/zhaarey/go-mp4tag"
+)
+
+// Metadata описывает теги, которые нужно записать в файл.
+type Metadata struct {
+	Title   string
+	Album   string
+	Artist  string
+	Year    string
+	Track   int
+	TrackOf int
+}
+
+// Apply записывает Metadata и обложку cover (JPEG) в аудиофайл по пути path.
+// Формат определяется по расширению файла; неизвестные расширения
+// возвращают ошибку, а не пропускаются молча.
+func Apply(path string, meta Metadata, cover []byte) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mp3":
+		return tagMP3(path, meta, cover)
+	case ".flac":
+		return tagFLAC(path, meta, cover)
+	case ".m4a":
+		return tagM4A(path, meta, cover)
+	default:
+		return fmt.Errorf("tag: unsupported file extension %q", filepath.Ext(path))
+	}
+}
+
+func tagMP3(path string, meta Metadata, cover []byte) error {
+	tag, err := id3v2.Open(path, id3v2.Options{Parse: true})
+	if err != nil {
+		return fmt.Errorf("tag: open mp3: %w", err)
+	}
+	defer tag.Close()
+
+	tag.SetTitle(meta.Title)
+	tag.SetAlbum(meta.Album)
+	tag.SetArtist(meta.Artist)
+	tag.SetYear(meta.Year)
+	if meta.Track > 0 {
+		tag.AddTextFrame(tag.CommonID("Track number/Position in set"), tag.DefaultEncoding(), strconv.Itoa(meta.Track))
+	}
+
+	if len(cover) > 0 {
+		tag.AddAttachedPicture(id3v2.PictureFrame{
+			Encoding:    id3v2.EncodingUTF8,
+			MimeType:    "image/jpeg",
+			PictureType: id3v2.PTFrontCover,
+			Description: "Front cover",
+			Picture:     cover,
+		})
+	}
+
+	return tag.Save()
+}
+
+func tagFLAC(path string, meta Metadata, cover []byte) error {
+	f, err := flac.ParseFile(path)
+	if err != nil {
+		return fmt.Errorf("tag: open flac: %w", err)
+	}
+
+	vorbis := flacvorbis.New()
+	vorbis.Add(flacvorbis.FIELD_TITLE, meta.Title)
+	vorbis.Add(flacvorbis.FIELD_ALBUM, meta.Album)
+	vorbis.Add(flacvorbis.FIELD_ARTIST, meta.Artist)
+	if meta.Year != "" {
+		vorbis.Add(flacvorbis.FIELD_DATE, meta.Year)
+	}
+	if meta.Track > 0 {
+		vorbis.Add(flacvorbis.FIELD_TRACKNUMBER, strconv.Itoa(meta.Track))
+	}
+	vorbisBlock := vorbis.Marshal()
+	f.Meta = append(removeBlocks(f.Meta, flac.VorbisComment, flac.Picture), &vorbisBlock)
+
+	if len(cover) > 0 {
+		picture, err := flacpicture.NewFromImageData(flacpicture.PictureTypeFrontCover, "Front cover", cover, "image/jpeg")
+		if err != nil {
+			return fmt.Errorf("tag: build flac picture: %w", err)
+		}
+		pictureBlock := picture.Marshal()
+		f.Meta = append(f.Meta, &pictureBlock)
+	}
+
+	return f.Save(path)
+}
+
+func tagM4A(path string, meta Metadata, cover []byte) error {
+	tagger, err := mp4tag.Open(path)
+	if err != nil {
+		return fmt.Errorf("tag: open m4a: %w", err)
+	}
+	defer tagger.Close()
+
+	m4aMeta := mp4tag.MP4Tags{
+		Title:  meta.Title,
+		Album:  meta.Album,
+		Artist: meta.Artist,
+	}
+	if year, err := strconv.Atoi(meta.Year); err == nil {
+		m4aMeta.Year = int32(year)
+	}
+	if meta.Track > 0 {
+		m4aMeta.TrackNumber = int16(meta.Track)
+		m4aMeta.TrackTotal = int16(meta.TrackOf)
+	}
+	if len(cover) > 0 {
+		m4aMeta.Pictures = []*mp4tag.MP4Picture{{Format: mp4tag.ImageTypeJPEG, Data: cover}}
+	}
+
+	return tagger.Write(&m4aMeta, []string{})
+}
+
+// removeBlocks возвращает meta без блоков заданных типов, чтобы старые
+// значения (например, предыдущий Vorbis-комментарий или обложка) не
+// дублировались при повторном тегировании.
+func removeBlocks(meta []*flac.MetaDataBlock, types ...flac.BlockType) []*flac.MetaDataBlock {
+	kept := meta[:0]
+	for _, block := range meta {
+		drop := false
+		for _, t := range types {
+			if block.Type == t {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			kept = append(kept, block)
+		}
+	}
+	return kept
+}